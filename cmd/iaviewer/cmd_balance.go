@@ -0,0 +1,50 @@
+package main
+
+import (
+	"encoding/hex"
+	"fmt"
+
+	"github.com/cosmos/cosmos-sdk/codec"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/address"
+	"github.com/cosmos/iavl"
+	"github.com/spf13/cobra"
+)
+
+// NewBalanceCmd prints the basecro balance of an account.
+func NewBalanceCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "balance <hex address>",
+		Short: "Print the basecro balance stored for an address",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			addr, err := hex.DecodeString(args[0])
+			if err != nil {
+				return fmt.Errorf("invalid address: %w", err)
+			}
+			tree, err := ReadTree(flagDBPath, flagDBBackend, flagVersion, []byte(flagPrefix))
+			if err != nil {
+				return fmt.Errorf("reading data: %w", err)
+			}
+			PrintBalance(tree, addr)
+			return nil
+		},
+	}
+}
+
+func PrintBalance(tree *iavl.MutableTree, addr []byte) {
+	key := []byte{0x02}
+	key = append(key, address.MustLengthPrefix(addr)...)
+	denom := "basecro"
+	key = append(key, []byte(denom)...)
+	_, value := tree.Get(key)
+	if value == nil {
+		fmt.Println("not found")
+		return
+	}
+	cdc := codec.NewLegacyAmino()
+	marshaler := codec.NewAminoCodec(cdc)
+	var balance sdk.Coin
+	marshaler.MustUnmarshal(value, &balance)
+	fmt.Println(balance.String())
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"crypto/sha256"
+	"fmt"
+
+	"github.com/cosmos/iavl"
+	"github.com/spf13/cobra"
+)
+
+// NewDataCmd prints every key in the tree alongside a hash of its value.
+func NewDataCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "data",
+		Short: "Print all keys in the tree, with a hash of each value",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			tree, err := ReadTree(flagDBPath, flagDBBackend, flagVersion, []byte(flagPrefix))
+			if err != nil {
+				return fmt.Errorf("reading data: %w", err)
+			}
+			start, err := parseKeyFlag(flagStartKey)
+			if err != nil {
+				return fmt.Errorf("invalid --start-key: %w", err)
+			}
+			end, err := parseKeyFlag(flagEndKey)
+			if err != nil {
+				return fmt.Errorf("invalid --end-key: %w", err)
+			}
+			PrintKeys(tree, start, end)
+			fmt.Printf("Hash: %X\n", tree.Hash())
+			fmt.Printf("Size: %X\n", tree.Size())
+			return nil
+		},
+	}
+	addRangeFlags(cmd)
+	return cmd
+}
+
+func PrintKeys(tree *iavl.MutableTree, start, end []byte) {
+	dec, hasDecoder := decoderFor(flagPrefix)
+	if flagDecode && hasDecoder {
+		fmt.Println("Printing all keys with decoded values")
+	} else {
+		fmt.Println("Printing all keys with hashed values (to detect diff)")
+	}
+
+	IterateRange(tree, start, end, true, flagOffset, flagLimit, func(key, value []byte) bool {
+		if flagDecode && hasDecoder {
+			printKey := dec.Key([]byte(flagPrefix), key)
+			rendered, err := dec.Value([]byte(flagPrefix), key, value)
+			if err != nil {
+				fmt.Printf("  %s\n    <decode error: %s>\n", printKey, err)
+				return false
+			}
+			fmt.Printf("  %s\n    %s\n", printKey, rendered)
+			return false
+		}
+
+		printKey := parseWeaveKey(key)
+		digest := sha256.Sum256(value)
+		fmt.Printf("  %s\n    %X\n", printKey, digest)
+		return false
+	})
+}
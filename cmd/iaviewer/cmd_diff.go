@@ -0,0 +1,196 @@
+package main
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/cosmos/iavl"
+	"github.com/spf13/cobra"
+)
+
+var (
+	flagDiffDBPathB    string
+	flagDiffDBBackendB string
+	flagDiffPrefixB    string
+	flagDiffVersionB   int
+	flagDiffOnlyKeys   bool
+	flagDiffIncludeVal bool
+)
+
+// NewDiffCmd compares two tree snapshots and prints the keys that changed.
+func NewDiffCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "diff",
+		Short: "Compare two IAVL tree versions and print added/removed/changed keys",
+		Long: `diff loads two trees (--db-path/--db-backend/--prefix/--version select the
+first, the "b" flags select the second) and merge-walks both in sorted key
+order, printing the keys that were added, removed or whose value hash
+changed. Peak memory is bounded by tree depth, not tree size.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			treeA, err := ReadTree(flagDBPath, flagDBBackend, flagVersion, []byte(flagPrefix))
+			if err != nil {
+				return fmt.Errorf("reading first tree: %w", err)
+			}
+			treeB, err := ReadTree(flagDiffDBPathB, flagDiffDBBackendB, flagDiffVersionB, []byte(flagDiffPrefixB))
+			if err != nil {
+				return fmt.Errorf("reading second tree: %w", err)
+			}
+			return PrintDiff(treeA, treeB)
+		},
+	}
+
+	cmd.Flags().StringVar(&flagDiffDBPathB, "db-path-b", "", "path to the second application db directory")
+	cmd.Flags().StringVar(&flagDiffDBBackendB, "db-backend-b", "goleveldb", "db backend for the second tree")
+	cmd.Flags().StringVar(&flagDiffPrefixB, "prefix-b", "", "iavl module prefix for the second tree")
+	cmd.Flags().IntVar(&flagDiffVersionB, "version-b", 0, "version to load for the second tree")
+	cmd.Flags().BoolVar(&flagDiffOnlyKeys, "only-keys", false, "print only the changed keys, no hashes or sizes (json output still carries kind and size_delta)")
+	cmd.Flags().BoolVar(&flagDiffIncludeVal, "include-values", false, "include the full old/new values, not just their hashes")
+	addOutputFlag(cmd)
+
+	return cmd
+}
+
+// diffKind identifies how a key changed between the two trees.
+type diffKind string
+
+const (
+	diffAdded   diffKind = "added"
+	diffRemoved diffKind = "removed"
+	diffChanged diffKind = "changed"
+)
+
+// diffEntry describes a single key that differs between tree A and tree B.
+type diffEntry struct {
+	Key       string   `json:"key"`
+	Kind      diffKind `json:"kind"`
+	OldHash   string   `json:"old_hash,omitempty"`
+	NewHash   string   `json:"new_hash,omitempty"`
+	OldSize   int      `json:"old_size,omitempty"`
+	NewSize   int      `json:"new_size,omitempty"`
+	SizeDelta int      `json:"size_delta"`
+	OldValue  []byte   `json:"old_value,omitempty"`
+	NewValue  []byte   `json:"new_value,omitempty"`
+}
+
+// kv is one entry read off an async tree iterator.
+type kv struct {
+	Key   []byte
+	Value []byte
+}
+
+// iterateAsync walks tree in ascending key order on its own goroutine and
+// publishes entries on the returned channel one at a time, so the merge-walk
+// in PrintDiff never has to materialize more than one pending entry per
+// side. Callers must invoke the returned stop func once they are done
+// draining (or abandoning) the channel.
+func iterateAsync(tree *iavl.MutableTree) (<-chan kv, func()) {
+	out := make(chan kv)
+	stop := make(chan struct{})
+	go func() {
+		defer close(out)
+		tree.Iterate(func(key, value []byte) bool {
+			select {
+			case out <- kv{Key: key, Value: value}:
+				return false
+			case <-stop:
+				return true
+			}
+		})
+	}()
+	return out, func() { close(stop) }
+}
+
+// PrintDiff merge-walks treeA and treeB in sorted key order and reports the
+// keys whose presence or value differs between them.
+func PrintDiff(treeA, treeB *iavl.MutableTree) error {
+	chA, stopA := iterateAsync(treeA)
+	chB, stopB := iterateAsync(treeB)
+	defer stopA()
+	defer stopB()
+
+	a, okA := <-chA
+	b, okB := <-chB
+
+	emit := func(e diffEntry) error {
+		if flagOutput == "json" {
+			enc := json.NewEncoder(os.Stdout)
+			return enc.Encode(e)
+		}
+		printDiffEntryText(e)
+		return nil
+	}
+
+	for okA || okB {
+		switch {
+		case okA && (!okB || bytes.Compare(a.Key, b.Key) < 0):
+			if err := emit(newDiffEntry(diffRemoved, a.Key, a.Value, nil)); err != nil {
+				return err
+			}
+			a, okA = <-chA
+
+		case okB && (!okA || bytes.Compare(b.Key, a.Key) < 0):
+			if err := emit(newDiffEntry(diffAdded, b.Key, nil, b.Value)); err != nil {
+				return err
+			}
+			b, okB = <-chB
+
+		default: // same key on both sides
+			if !bytes.Equal(a.Value, b.Value) {
+				if err := emit(newDiffEntry(diffChanged, a.Key, a.Value, b.Value)); err != nil {
+					return err
+				}
+			}
+			a, okA = <-chA
+			b, okB = <-chB
+		}
+	}
+
+	return nil
+}
+
+func newDiffEntry(kind diffKind, key, oldValue, newValue []byte) diffEntry {
+	e := diffEntry{
+		Key:       parseWeaveKey(key),
+		Kind:      kind,
+		SizeDelta: len(newValue) - len(oldValue),
+	}
+	if flagDiffOnlyKeys {
+		return e
+	}
+	if oldValue != nil {
+		h := sha256.Sum256(oldValue)
+		e.OldHash = fmt.Sprintf("%X", h)
+		e.OldSize = len(oldValue)
+	}
+	if newValue != nil {
+		h := sha256.Sum256(newValue)
+		e.NewHash = fmt.Sprintf("%X", h)
+		e.NewSize = len(newValue)
+	}
+	if flagDiffIncludeVal {
+		e.OldValue = oldValue
+		e.NewValue = newValue
+	}
+	return e
+}
+
+func printDiffEntryText(e diffEntry) {
+	sign := map[diffKind]string{diffAdded: "+", diffRemoved: "-", diffChanged: "~"}[e.Kind]
+	if flagDiffOnlyKeys {
+		fmt.Printf("%s %s\n", sign, e.Key)
+		return
+	}
+	fmt.Printf("%s %s  old=%s(%d) new=%s(%d) delta=%d\n",
+		sign, e.Key, e.OldHash, e.OldSize, e.NewHash, e.NewSize, e.SizeDelta)
+	if flagDiffIncludeVal {
+		if e.OldValue != nil {
+			fmt.Printf("    old value: %X\n", e.OldValue)
+		}
+		if e.NewValue != nil {
+			fmt.Printf("    new value: %X\n", e.NewValue)
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"encoding/hex"
+	"fmt"
+
+	"github.com/cosmos/cosmos-sdk/codec"
+	"github.com/cosmos/cosmos-sdk/codec/types"
+	authtypes "github.com/cosmos/cosmos-sdk/x/auth/types"
+	"github.com/cosmos/iavl"
+	"github.com/spf13/cobra"
+	ethermint "github.com/tharsis/ethermint/types"
+)
+
+// NewNonceCmd prints the account sequence (nonce) stored for an address.
+func NewNonceCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "nonce <hex address>",
+		Short: "Print the account sequence stored for an address",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			addr, err := hex.DecodeString(args[0])
+			if err != nil {
+				return fmt.Errorf("invalid address: %w", err)
+			}
+			tree, err := ReadTree(flagDBPath, flagDBBackend, flagVersion, []byte(flagPrefix))
+			if err != nil {
+				return fmt.Errorf("reading data: %w", err)
+			}
+			return PrintAccount(tree, addr)
+		},
+	}
+}
+
+func PrintAccount(tree *iavl.MutableTree, addr []byte) error {
+	key := authtypes.AddressStoreKey(addr)
+	_, value := tree.Get(key)
+	if value == nil {
+		fmt.Println("not found")
+		return nil
+	}
+
+	interfaceRegistry := types.NewInterfaceRegistry()
+	authtypes.RegisterInterfaces(interfaceRegistry)
+	ethermint.RegisterInterfaces(interfaceRegistry)
+	marshaler := codec.NewProtoCodec(interfaceRegistry)
+
+	var acc authtypes.AccountI
+	if err := marshaler.UnmarshalInterface(value, &acc); err != nil {
+		return fmt.Errorf("unmarshaling account: %w", err)
+	}
+	fmt.Println(acc.GetSequence())
+	return nil
+}
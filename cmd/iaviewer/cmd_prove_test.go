@@ -0,0 +1,61 @@
+package main
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+// TestProveVerifyRoundTrip checks that the output of "prove" can be fed
+// straight into "verify"'s flags, for both a present and an absent key.
+// This is exactly the workflow iaviewer's --key/--value handling broke:
+// prove printed bare hex, which verify's parseKeyFlag silently re-read as
+// ascii instead of decoding.
+func TestProveVerifyRoundTrip(t *testing.T) {
+	tree := newTestTree(t, map[string]string{
+		"alpha": "1",
+		"bravo": "2",
+		"charl": "3",
+	})
+
+	origOutput := flagOutput
+	flagOutput = "json"
+	defer func() { flagOutput = origOutput }()
+
+	t.Run("membership", func(t *testing.T) {
+		out := captureStdout(t, func() {
+			if err := PrintProof(tree, []byte("bravo")); err != nil {
+				t.Fatalf("PrintProof: %v", err)
+			}
+		})
+
+		var env proofEnvelope
+		if err := json.Unmarshal([]byte(strings.TrimSpace(out)), &env); err != nil {
+			t.Fatalf("decoding prove output: %v", err)
+		}
+
+		if err := RunVerify(env.Proof, env.Root, env.Key, env.Value); err != nil {
+			t.Fatalf("RunVerify: %v", err)
+		}
+	})
+
+	t.Run("non-membership", func(t *testing.T) {
+		out := captureStdout(t, func() {
+			if err := PrintProof(tree, []byte("missing")); err != nil {
+				t.Fatalf("PrintProof: %v", err)
+			}
+		})
+
+		var env proofEnvelope
+		if err := json.Unmarshal([]byte(strings.TrimSpace(out)), &env); err != nil {
+			t.Fatalf("decoding prove output: %v", err)
+		}
+		if env.Value != "" {
+			t.Fatalf("expected empty value for a non-membership proof, got %q", env.Value)
+		}
+
+		if err := RunVerify(env.Proof, env.Root, env.Key, env.Value); err != nil {
+			t.Fatalf("RunVerify: %v", err)
+		}
+	})
+}
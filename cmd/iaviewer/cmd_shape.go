@@ -0,0 +1,30 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/cosmos/iavl"
+	"github.com/spf13/cobra"
+)
+
+// NewShapeCmd prints the shape of the tree.
+func NewShapeCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "shape",
+		Short: "Print the shape of the tree",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			tree, err := ReadTree(flagDBPath, flagDBBackend, flagVersion, []byte(flagPrefix))
+			if err != nil {
+				return fmt.Errorf("reading data: %w", err)
+			}
+			PrintShape(tree)
+			return nil
+		},
+	}
+}
+
+func PrintShape(tree *iavl.MutableTree) {
+	shape := tree.RenderShape("  ", nodeEncoder)
+	fmt.Println(strings.Join(shape, "\n"))
+}
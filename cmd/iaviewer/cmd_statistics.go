@@ -0,0 +1,151 @@
+package main
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"os"
+
+	"github.com/cosmos/iavl"
+	"github.com/spf13/cobra"
+)
+
+// modules lists the well-known cosmos-sdk module store prefixes that
+// PrintStatistics walks. It always walks all of them; --prefix is not
+// consulted.
+var modules = []string{
+	"capability",
+	"params",
+	"transfer",
+	"staking",
+	"slashing",
+	"distribution",
+	"feegrant",
+	"upgrade",
+	"authz",
+	"evidence",
+	"feemarket",
+	"gravity",
+	"gov",
+	"cronos",
+	"ibc",
+	"bank",
+	"mint",
+	"acc",
+	"evm",
+}
+
+// NewStatisticsCmd prints per-module key/value size statistics.
+func NewStatisticsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "statistics",
+		Short: "Print key/value size statistics for every known module store",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			start, err := parseKeyFlag(flagStartKey)
+			if err != nil {
+				return fmt.Errorf("invalid --start-key: %w", err)
+			}
+			end, err := parseKeyFlag(flagEndKey)
+			if err != nil {
+				return fmt.Errorf("invalid --end-key: %w", err)
+			}
+			PrintStatistics(flagDBPath, flagDBBackend, flagVersion, start, end)
+			return nil
+		},
+	}
+	addRangeFlags(cmd)
+	return cmd
+}
+
+func PrintStatistics(dbPath, backend string, version int, start, end []byte) {
+	for idx, mod := range modules {
+		prefix := fmt.Sprintf("s/k:%s/", mod)
+		tree, err := ReadTree(dbPath, backend, version, []byte(prefix))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error reading %s data: %s\n", mod, err)
+			continue
+		}
+
+		fmt.Printf("iterating over %s  (%d/%d)\n", mod, idx+1, len(modules))
+		fmt.Printf("tree size:%d height:%d\n", tree.Size(), tree.Height())
+		PrintKeysWithValueSize(tree, prefix, start, end)
+		fmt.Println("")
+	}
+}
+
+// PrintKeysWithValueSize streams over [start, end) computing running totals
+// plus two sketches that never grow with the number of keys visited: a
+// HyperLogLog estimating cardinality of each 1-byte key sub-prefix, and a
+// tDigest estimating key/value size quantiles. Progress goes to stderr so
+// the key stream on stdout stays pipe-friendly.
+func PrintKeysWithValueSize(tree *iavl.MutableTree, prefix string, start, end []byte) {
+	dec, hasDecoder := decoderFor(prefix)
+	if flagDecode && hasDecoder {
+		fmt.Println("Printing all keys with decoded values")
+	} else {
+		fmt.Println("Printing all keys with hashed values (to detect diff)")
+	}
+
+	count := int64(0)
+	keySizeTotal := 0
+	valueSizeTotal := 0
+	keyMaxSize := int64(0)
+	valueMaxSize := int64(0)
+	keySizes := newTDigest(100)
+	valueSizes := newTDigest(100)
+	subPrefixCardinality := map[byte]*hyperLogLog{}
+
+	IterateRange(tree, start, end, true, flagOffset, flagLimit, func(key, value []byte) bool {
+		if flagDecode && hasDecoder {
+			printKey := dec.Key([]byte(prefix), key)
+			if rendered, err := dec.Value([]byte(prefix), key, value); err != nil {
+				fmt.Printf("  %s\n    <decode error: %s>\n", printKey, err)
+			} else {
+				fmt.Printf("  %s\n    %s\n", printKey, rendered)
+			}
+		} else {
+			printKey := parseWeaveKey(key)
+			digest := sha256.Sum256(value)
+			fmt.Printf("  %s\n    %X\n", printKey, digest)
+		}
+
+		count++
+		keySizeTotal += len(key)
+		valueSizeTotal += len(value)
+		keyMaxSize = Max(keyMaxSize, int64(len(key)))
+		valueMaxSize = Max(valueMaxSize, int64(len(value)))
+		keySizes.Add(float64(len(key)))
+		valueSizes.Add(float64(len(value)))
+
+		if len(key) > 0 {
+			sub := key[0]
+			if subPrefixCardinality[sub] == nil {
+				subPrefixCardinality[sub] = newHyperLogLog(8)
+			}
+			subPrefixCardinality[sub].Add(key)
+		}
+
+		if tree.Size() >= 100 && count%(tree.Size()/100) == 0 {
+			fmt.Fprintf(os.Stderr, "progress:  %d%%\n", count*100/tree.Size())
+		}
+
+		return false
+	})
+
+	fmt.Printf("%d keys, keySizeTotal: %d, valueSizeTotal: %d\n", count, keySizeTotal, valueSizeTotal)
+	if count > 0 {
+		fmt.Printf("avg key size:%d, avg value size:%d\n", int64(keySizeTotal)/count, int64(valueSizeTotal)/count)
+	}
+	fmt.Printf("max key size:%d, max value size:%d\n", keyMaxSize, valueMaxSize)
+	fmt.Printf("key size p50/p99: %.0f/%.0f, value size p50/p99: %.0f/%.0f\n",
+		keySizes.Quantile(0.5), keySizes.Quantile(0.99), valueSizes.Quantile(0.5), valueSizes.Quantile(0.99))
+	for sub, hll := range subPrefixCardinality {
+		fmt.Printf("sub-prefix 0x%02X: ~%d distinct keys\n", sub, int64(hll.Estimate()))
+	}
+}
+
+func Max(x, y int64) int64 {
+	if x > y {
+		return x
+	}
+	return y
+}
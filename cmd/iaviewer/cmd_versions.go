@@ -0,0 +1,32 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/cosmos/iavl"
+	"github.com/spf13/cobra"
+)
+
+// NewVersionsCmd prints the versions available in the tree.
+func NewVersionsCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "versions",
+		Short: "Print the versions available in the tree",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			tree, err := ReadTree(flagDBPath, flagDBBackend, flagVersion, []byte(flagPrefix))
+			if err != nil {
+				return fmt.Errorf("reading data: %w", err)
+			}
+			PrintVersions(tree)
+			return nil
+		},
+	}
+}
+
+func PrintVersions(tree *iavl.MutableTree) {
+	versions := tree.AvailableVersions()
+	fmt.Println("Available versions:")
+	for _, v := range versions {
+		fmt.Printf("  %d\n", v)
+	}
+}
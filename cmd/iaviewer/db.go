@@ -0,0 +1,43 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/cosmos/iavl"
+	dbm "github.com/tendermint/tm-db"
+)
+
+// OpenDB opens the application db at dir using the given backend. Unlike the
+// original ad-hoc loader, it does not try to strip a ".db" suffix off dir:
+// the caller is expected to pass the raw name/directory tm-db itself expects.
+func OpenDB(dir string, backend string) (dbm.DB, error) {
+	name := filepath.Base(dir)
+	home := filepath.Dir(dir)
+	return dbm.NewDB(name, dbm.BackendType(backend), home)
+}
+
+// ReadTree loads an iavl tree from dir using the given db backend.
+// If version is 0, the latest version is loaded, otherwise the named version.
+// The prefix selects which module's iavl tree to read; an empty prefix reads
+// the tree stored at the root of the db.
+func ReadTree(dir, backend string, version int, prefix []byte) (*iavl.MutableTree, error) {
+	db, err := OpenDB(dir, backend)
+	if err != nil {
+		return nil, err
+	}
+	if len(prefix) != 0 {
+		db = dbm.NewPrefixDB(db, prefix)
+	}
+
+	tree, err := iavl.NewMutableTree(db, DefaultCacheSize)
+	if err != nil {
+		return nil, err
+	}
+	ver, err := tree.LoadVersion(int64(version))
+	if err != nil {
+		return nil, err
+	}
+	fmt.Printf("Got version: %d\n", ver)
+	return tree, nil
+}
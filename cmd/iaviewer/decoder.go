@@ -0,0 +1,34 @@
+package main
+
+// Decoder renders a module's raw IAVL keys and values into human readable
+// strings. Each module store (selected by its "s/k:<module>/" prefix) has
+// its own key layout and proto/amino types, so decoding is dispatched
+// through a registry keyed on that prefix rather than hard-coded per field.
+type Decoder interface {
+	// Key renders a human-readable form of key, given the store prefix the
+	// key was read under, e.g. "s/k:bank/".
+	Key(prefix, key []byte) string
+	// Value decodes value into a human-readable (typically JSON) string.
+	Value(prefix, key, value []byte) (string, error)
+}
+
+var decoders = map[string]Decoder{}
+
+// RegisterDecoder registers d as the Decoder used for keys and values read
+// from the module store under prefix, e.g.:
+//
+//	RegisterDecoder("s/k:cronos/", cronosDecoder{})
+//
+// Decoders for chain-specific modules not shipped with iaviewer (cronos,
+// gravity, feemarket, ...) are added by calling this from an init func in a
+// file of this package, as decoders.go does for the built-in ones; iaviewer
+// is a main package, so other modules cannot import and call it.
+func RegisterDecoder(prefix string, d Decoder) {
+	decoders[prefix] = d
+}
+
+// decoderFor returns the Decoder registered for prefix, if any.
+func decoderFor(prefix string) (Decoder, bool) {
+	d, ok := decoders[prefix]
+	return d, ok
+}
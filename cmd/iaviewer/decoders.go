@@ -0,0 +1,200 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/cosmos/cosmos-sdk/codec"
+	"github.com/cosmos/cosmos-sdk/codec/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	authtypes "github.com/cosmos/cosmos-sdk/x/auth/types"
+	distrtypes "github.com/cosmos/cosmos-sdk/x/distribution/types"
+	govtypes "github.com/cosmos/cosmos-sdk/x/gov/types"
+	minttypes "github.com/cosmos/cosmos-sdk/x/mint/types"
+	stakingtypes "github.com/cosmos/cosmos-sdk/x/staking/types"
+	ibcconnectiontypes "github.com/cosmos/ibc-go/v3/modules/core/03-connection/types"
+	ibcchanneltypes "github.com/cosmos/ibc-go/v3/modules/core/04-channel/types"
+	ethermint "github.com/tharsis/ethermint/types"
+)
+
+func init() {
+	RegisterDecoder("s/k:bank/", bankDecoder{})
+	RegisterDecoder("s/k:staking/", stakingDecoder{})
+	RegisterDecoder("s/k:gov/", govDecoder{})
+	RegisterDecoder("s/k:distribution/", distributionDecoder{})
+	RegisterDecoder("s/k:mint/", mintDecoder{})
+	RegisterDecoder("s/k:evm/", evmDecoder{})
+	RegisterDecoder("s/k:ibc/", ibcDecoder{})
+}
+
+// moduleCodec builds the proto codec used by the built-in decoders. It is
+// rebuilt per call rather than cached as a package var so RegisterInterfaces
+// from a future module can be added without touching unrelated decoders.
+func moduleCodec() *codec.ProtoCodec {
+	registry := types.NewInterfaceRegistry()
+	authtypes.RegisterInterfaces(registry)
+	stakingtypes.RegisterInterfaces(registry)
+	govtypes.RegisterInterfaces(registry)
+	ethermint.RegisterInterfaces(registry)
+	return codec.NewProtoCodec(registry)
+}
+
+// decodeProtoJSON unmarshals value as msg and renders it as JSON.
+func decodeProtoJSON(value []byte, msg codec.ProtoMarshaler) (string, error) {
+	cdc := moduleCodec()
+	if err := cdc.Unmarshal(value, msg); err != nil {
+		return "", fmt.Errorf("unmarshaling %T: %w", msg, err)
+	}
+	bz, err := cdc.MarshalJSON(msg)
+	if err != nil {
+		return "", fmt.Errorf("marshaling %T as json: %w", msg, err)
+	}
+	return string(bz), nil
+}
+
+// bankDecoder renders x/bank balance entries (key 0x02 | addr | denom).
+type bankDecoder struct{}
+
+func (bankDecoder) Key(prefix, key []byte) string {
+	// balances key layout: 0x02 | addrLen (1 byte) | addr | denom
+	if len(key) < 2 || key[0] != 0x02 {
+		return parseWeaveKey(key)
+	}
+	addrLen := int(key[1])
+	if len(key) < 2+addrLen {
+		return parseWeaveKey(key)
+	}
+	addr := key[2 : 2+addrLen]
+	denom := key[2+addrLen:]
+	return fmt.Sprintf("balance:%X:%s", addr, denom)
+}
+
+func (bankDecoder) Value(prefix, key, value []byte) (string, error) {
+	cdc := codec.NewLegacyAmino()
+	marshaler := codec.NewAminoCodec(cdc)
+	var coin sdk.Coin
+	if err := marshaler.Unmarshal(value, &coin); err != nil {
+		return "", fmt.Errorf("unmarshaling balance: %w", err)
+	}
+	return coin.String(), nil
+}
+
+// stakingDecoder renders x/staking validator, delegation and unbonding
+// delegation records.
+type stakingDecoder struct{}
+
+func (stakingDecoder) Key(prefix, key []byte) string {
+	return parseWeaveKey(key)
+}
+
+func (stakingDecoder) Value(prefix, key, value []byte) (string, error) {
+	if len(key) == 0 {
+		return "", fmt.Errorf("empty staking key")
+	}
+	switch key[0] {
+	case 0x21: // ValidatorsKey
+		return decodeProtoJSON(value, &stakingtypes.Validator{})
+	case 0x31: // DelegationKey
+		return decodeProtoJSON(value, &stakingtypes.Delegation{})
+	case 0x32: // UnbondingDelegationKey
+		return decodeProtoJSON(value, &stakingtypes.UnbondingDelegation{})
+	default:
+		return "", fmt.Errorf("unrecognized staking key prefix byte 0x%X", key[0])
+	}
+}
+
+// govDecoder renders x/gov proposal records.
+type govDecoder struct{}
+
+func (govDecoder) Key(prefix, key []byte) string {
+	return parseWeaveKey(key)
+}
+
+func (govDecoder) Value(prefix, key, value []byte) (string, error) {
+	return decodeProtoJSON(value, &govtypes.Proposal{})
+}
+
+// distributionDecoder renders x/distribution fee pool, rewards and
+// commission records.
+type distributionDecoder struct{}
+
+func (distributionDecoder) Key(prefix, key []byte) string {
+	return parseWeaveKey(key)
+}
+
+func (distributionDecoder) Value(prefix, key, value []byte) (string, error) {
+	if len(key) == 0 {
+		return "", fmt.Errorf("empty distribution key")
+	}
+	switch key[0] {
+	case 0x00: // FeePoolKey
+		return decodeProtoJSON(value, &distrtypes.FeePool{})
+	case 0x02: // ValidatorOutstandingRewardsPrefix
+		return decodeProtoJSON(value, &distrtypes.ValidatorOutstandingRewards{})
+	case 0x04: // DelegatorStartingInfoPrefix
+		return decodeProtoJSON(value, &distrtypes.DelegatorStartingInfo{})
+	case 0x07: // ValidatorAccumulatedCommissionPrefix
+		return decodeProtoJSON(value, &distrtypes.ValidatorAccumulatedCommission{})
+	default:
+		return "", fmt.Errorf("unrecognized distribution key prefix byte 0x%X", key[0])
+	}
+}
+
+// mintDecoder renders the x/mint minter record.
+type mintDecoder struct{}
+
+func (mintDecoder) Key(prefix, key []byte) string {
+	return parseWeaveKey(key)
+}
+
+func (mintDecoder) Value(prefix, key, value []byte) (string, error) {
+	return decodeProtoJSON(value, &minttypes.Minter{})
+}
+
+// evmDecoder renders ethermint's evm storage slots and account records. The
+// store interleaves 20-byte address keys (account state, an EthAccount
+// packed as an Any) with 20+32-byte address|hash keys (storage slots, a
+// raw 32-byte word with no proto type to decode into).
+type evmDecoder struct{}
+
+func (evmDecoder) Key(prefix, key []byte) string {
+	switch len(key) {
+	case 20:
+		return fmt.Sprintf("account:%X", key)
+	case 52:
+		return fmt.Sprintf("storage:%X:%X", key[:20], key[20:])
+	default:
+		return fmt.Sprintf("%X", key)
+	}
+}
+
+func (evmDecoder) Value(prefix, key, value []byte) (string, error) {
+	if len(key) != 20 {
+		// storage slot: just a raw word, nothing to decode
+		return fmt.Sprintf("%X", value), nil
+	}
+
+	cdc := moduleCodec()
+	var acc authtypes.AccountI
+	if err := cdc.UnmarshalInterface(value, &acc); err != nil {
+		return "", fmt.Errorf("unmarshaling EthAccount: %w", err)
+	}
+	bz, err := cdc.MarshalInterfaceJSON(acc)
+	if err != nil {
+		return "", fmt.Errorf("marshaling EthAccount as json: %w", err)
+	}
+	return string(bz), nil
+}
+
+// ibcDecoder renders ibc-go channel and connection state records.
+type ibcDecoder struct{}
+
+func (ibcDecoder) Key(prefix, key []byte) string {
+	return string(key)
+}
+
+func (ibcDecoder) Value(prefix, key, value []byte) (string, error) {
+	if s, err := decodeProtoJSON(value, &ibcchanneltypes.Channel{}); err == nil {
+		return s, nil
+	}
+	return decodeProtoJSON(value, &ibcconnectiontypes.ConnectionEnd{})
+}
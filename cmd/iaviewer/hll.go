@@ -0,0 +1,54 @@
+package main
+
+import (
+	"hash/fnv"
+	"math"
+)
+
+// hyperLogLog is a small, fixed-precision HyperLogLog sketch. It estimates
+// the number of distinct byte strings fed to it in O(2^precision) memory,
+// regardless of how many are added, which is what lets PrintStatistics
+// report per-subprefix cardinality without buffering a single key.
+type hyperLogLog struct {
+	registers []uint8
+	p         uint
+}
+
+func newHyperLogLog(precision uint) *hyperLogLog {
+	return &hyperLogLog{registers: make([]uint8, 1<<precision), p: precision}
+}
+
+func (h *hyperLogLog) Add(data []byte) {
+	sum := fnv.New64a()
+	_, _ = sum.Write(data)
+	x := sum.Sum64()
+
+	idx := x >> (64 - h.p)
+	w := x<<h.p | (1 << (h.p - 1)) // sentinel bit in the shifted-in low bits so rank is bounded
+	rank := uint8(1)
+	for w&(1<<63) == 0 {
+		rank++
+		w <<= 1
+	}
+	if rank > h.registers[idx] {
+		h.registers[idx] = rank
+	}
+}
+
+func (h *hyperLogLog) Estimate() float64 {
+	m := float64(len(h.registers))
+	sum := 0.0
+	zeros := 0
+	for _, r := range h.registers {
+		sum += 1 / math.Pow(2, float64(r))
+		if r == 0 {
+			zeros++
+		}
+	}
+	alpha := 0.7213 / (1 + 1.079/m)
+	estimate := alpha * m * m / sum
+	if estimate <= 2.5*m && zeros > 0 {
+		return m * math.Log(m/float64(zeros))
+	}
+	return estimate
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"encoding/hex"
+	"strings"
+
+	"github.com/cosmos/iavl"
+)
+
+// IterateRange walks tree over [start, end) in the given direction,
+// skipping the first offset matches and stopping once limit entries have
+// been delivered to fn (limit <= 0 means unbounded). It wraps iavl's own
+// range iterator so a bounded pass over a huge module store still streams
+// from the db in sorted order instead of buffering every key first.
+func IterateRange(tree *iavl.MutableTree, start, end []byte, ascending bool, offset, limit int, fn func(key, value []byte) bool) {
+	seen := 0
+	delivered := 0
+	tree.IterateRange(start, end, ascending, func(key, value []byte) bool {
+		seen++
+		if seen <= offset {
+			return false
+		}
+		stop := fn(key, value)
+		delivered++
+		if stop {
+			return true
+		}
+		return limit > 0 && delivered >= limit
+	})
+}
+
+// parseKeyFlag interprets s as hex when it has a "0x" prefix and as raw
+// ascii otherwise. An empty string means "no bound".
+func parseKeyFlag(s string) ([]byte, error) {
+	if s == "" {
+		return nil, nil
+	}
+	if strings.HasPrefix(s, "0x") {
+		return hex.DecodeString(s[2:])
+	}
+	return []byte(s), nil
+}
@@ -0,0 +1,11 @@
+package main
+
+import "github.com/spf13/cobra"
+
+// addOutputFlag registers --output on cmd. Only commands that actually
+// branch on flagOutput should call this — keeping it off the persistent
+// flag set means --output never silently no-ops on a command that just
+// prints text regardless.
+func addOutputFlag(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&flagOutput, "output", "text", "output format: text|json")
+}
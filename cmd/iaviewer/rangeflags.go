@@ -0,0 +1,20 @@
+package main
+
+import "github.com/spf13/cobra"
+
+// Shared by any command that walks a bounded slice of a tree's keys instead
+// of the whole thing.
+var (
+	flagStartKey string
+	flagEndKey   string
+	flagLimit    int
+	flagOffset   int
+)
+
+// addRangeFlags registers --start-key/--end-key/--limit/--offset on cmd.
+func addRangeFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&flagStartKey, "start-key", "", "inclusive start key, hex (0x...) or ascii")
+	cmd.Flags().StringVar(&flagEndKey, "end-key", "", "exclusive end key, hex (0x...) or ascii")
+	cmd.Flags().IntVar(&flagLimit, "limit", 0, "maximum number of keys to visit (0 = unbounded)")
+	cmd.Flags().IntVar(&flagOffset, "offset", 0, "number of matching keys to skip before the first one visited")
+}
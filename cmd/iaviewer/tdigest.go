@@ -0,0 +1,72 @@
+package main
+
+import (
+	"math"
+	"sort"
+)
+
+// centroid is one cluster of a tDigest.
+type centroid struct {
+	mean  float64
+	count int64
+}
+
+// tDigest is a compact streaming quantile sketch: it buffers at most
+// 4*maxCentroids clusters, and whenever it grows past that it merges the
+// closest adjacent pairs until maxCentroids remain, so its memory footprint
+// never depends on how many values were added. It trades some quantile
+// precision for that boundedness.
+type tDigest struct {
+	centroids    []centroid
+	maxCentroids int
+	count        int64
+}
+
+func newTDigest(maxCentroids int) *tDigest {
+	return &tDigest{maxCentroids: maxCentroids}
+}
+
+func (t *tDigest) Add(value float64) {
+	t.centroids = append(t.centroids, centroid{mean: value, count: 1})
+	t.count++
+	if len(t.centroids) > t.maxCentroids*4 {
+		t.compress()
+	}
+}
+
+func (t *tDigest) compress() {
+	sort.Slice(t.centroids, func(i, j int) bool { return t.centroids[i].mean < t.centroids[j].mean })
+	for len(t.centroids) > t.maxCentroids {
+		best := 0
+		bestDist := math.MaxFloat64
+		for i := 0; i < len(t.centroids)-1; i++ {
+			if d := t.centroids[i+1].mean - t.centroids[i].mean; d < bestDist {
+				bestDist = d
+				best = i
+			}
+		}
+		a, b := t.centroids[best], t.centroids[best+1]
+		merged := centroid{
+			mean:  (a.mean*float64(a.count) + b.mean*float64(b.count)) / float64(a.count+b.count),
+			count: a.count + b.count,
+		}
+		t.centroids = append(t.centroids[:best], append([]centroid{merged}, t.centroids[best+2:]...)...)
+	}
+}
+
+// Quantile returns an estimate of the qth quantile, 0 <= q <= 1.
+func (t *tDigest) Quantile(q float64) float64 {
+	if len(t.centroids) == 0 {
+		return 0
+	}
+	t.compress()
+	target := q * float64(t.count)
+	var cumulative int64
+	for _, c := range t.centroids {
+		cumulative += c.count
+		if float64(cumulative) >= target {
+			return c.mean
+		}
+	}
+	return t.centroids[len(t.centroids)-1].mean
+}